@@ -0,0 +1,25 @@
+// Package dns holds the DNS providers codebase and a factory to select them by name.
+package dns
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/providers/dns/duckdns"
+	"github.com/go-acme/lego/v4/providers/dns/gandi"
+	"github.com/go-acme/lego/v4/providers/dns/hostingde"
+)
+
+// NewDNSChallengeProviderByName returns a new DNS challenge.Provider configured for the given provider name.
+func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
+	switch name {
+	case "duckdns":
+		return duckdns.NewDNSProvider()
+	case "gandi":
+		return gandi.NewDNSProvider()
+	case "hostingde":
+		return hostingde.NewDNSProvider()
+	default:
+		return nil, fmt.Errorf("unrecognized DNS provider: %s", name)
+	}
+}
@@ -0,0 +1,350 @@
+// Package duckdns implements a DNS provider for solving the DNS-01 challenge using DuckDNS.
+package duckdns
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+)
+
+// DuckDNS API reference: https://www.duckdns.org/spec.jsp
+
+const defaultBaseURL = "https://www.duckdns.org/update"
+
+const challengePrefix = "_acme-challenge."
+
+const mainDomainSuffix = ".duckdns.org"
+
+// Environment variables names.
+const (
+	envNamespace = "DUCKDNS_"
+
+	EnvToken = envNamespace + "TOKEN"
+
+	// EnvVerbose opts a user into DuckDNS's `&verbose=true` multi-record workaround, which is
+	// required when more than one TXT value needs to be live under the same main domain at once
+	// (e.g. issuing for both the apex and a wildcard in the same order).
+	EnvVerbose = envNamespace + "VERBOSE"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	BaseURL string
+	Token   string
+
+	// Verbose opts into DuckDNS's multi-record workaround, allowing more than one TXT value to be
+	// pending at once for the same main domain (see EnvVerbose).
+	Verbose bool
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		Verbose:            env.GetOrDefaultBool(EnvVerbose, false),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 60*time.Second),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// pendingChallenge tracks the TXT values currently requested for a main domain, so that
+// concurrent challenges under it (e.g. wildcard + apex) don't clobber each other.
+type pendingChallenge struct {
+	counts map[string]int
+	order  []string
+}
+
+func (p *pendingChallenge) add(value string) {
+	if p.counts[value] == 0 {
+		p.order = append(p.order, value)
+	}
+
+	p.counts[value]++
+}
+
+// txtValue returns the TXT value to send to DuckDNS for the currently accumulated set.
+// DuckDNS only stores a single TXT value per domain, so more than one distinct pending value is
+// only allowed once the caller has opted into the `&verbose=true` multi-record workaround.
+func (p *pendingChallenge) txtValue(verbose bool) (string, error) {
+	if verbose {
+		return strings.Join(p.order, ","), nil
+	}
+
+	if len(p.order) > 1 {
+		return "", fmt.Errorf("%d concurrent challenges requested but DuckDNS only stores a single TXT value; set %s to opt into the multi-record workaround", len(p.order), EnvVerbose)
+	}
+
+	return p.order[0], nil
+}
+
+func (p *pendingChallenge) remove(value string) {
+	if p.counts[value] == 0 {
+		return
+	}
+
+	p.counts[value]--
+	if p.counts[value] == 0 {
+		delete(p.counts, value)
+
+		for i, v := range p.order {
+			if v == value {
+				p.order = append(p.order[:i], p.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+
+	// domainLocks serializes Present/CleanUp per main domain, so that concurrent challenges for
+	// unrelated domains don't block on each other's outbound HTTP call.
+	domainLocksMu sync.Mutex
+	domainLocks   map[string]*sync.Mutex
+
+	// pendingMu guards only the pending map itself; once a *pendingChallenge is obtained, access to
+	// it is already serialized by the matching domainLocks entry.
+	pendingMu sync.Mutex
+	pending   map[string]*pendingChallenge
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for DuckDNS.
+// Credentials must be passed in the environment variable: DUCKDNS_TOKEN.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvToken)
+	if err != nil {
+		return nil, fmt.Errorf("duckdns: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Token = values[EnvToken]
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for DuckDNS.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("duckdns: the configuration of the DNS provider is nil")
+	}
+
+	if config.Token == "" {
+		return nil, errors.New("duckdns: credentials missing")
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	return &DNSProvider{
+		config:      config,
+		domainLocks: make(map[string]*sync.Mutex),
+		pending:     make(map[string]*pendingChallenge),
+	}, nil
+}
+
+// Present creates a TXT record to fulfil the DNS-01 challenge.
+// Concurrent challenges under the same main domain (e.g. wildcard + apex) are coalesced: their
+// values are accumulated and the record is reissued on every call.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	mainDomain := getMainDomain(info.EffectiveFQDN)
+	if mainDomain == "" {
+		return fmt.Errorf("duckdns: unable to determine the main domain for %s", info.EffectiveFQDN)
+	}
+
+	mu := d.domainLock(mainDomain)
+	mu.Lock()
+	defer mu.Unlock()
+
+	challenge := d.getOrCreatePending(mainDomain)
+
+	challenge.add(info.Value)
+
+	txt, err := challenge.txtValue(d.config.Verbose)
+	if err != nil {
+		d.rollbackPending(mainDomain, challenge, info.Value)
+		return fmt.Errorf("duckdns: %s: %w", mainDomain, err)
+	}
+
+	if err := d.updateTxtRecord(mainDomain, txt, false); err != nil {
+		d.rollbackPending(mainDomain, challenge, info.Value)
+		return err
+	}
+
+	return nil
+}
+
+// CleanUp removes this challenge's TXT value, clearing the record once no challenge still needs it.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	mainDomain := getMainDomain(info.EffectiveFQDN)
+	if mainDomain == "" {
+		return fmt.Errorf("duckdns: unable to determine the main domain for %s", info.EffectiveFQDN)
+	}
+
+	mu := d.domainLock(mainDomain)
+	mu.Lock()
+	defer mu.Unlock()
+
+	d.pendingMu.Lock()
+	challenge, ok := d.pending[mainDomain]
+	d.pendingMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	challenge.remove(info.Value)
+
+	if len(challenge.order) == 0 {
+		if err := d.updateTxtRecord(mainDomain, "", true); err != nil {
+			return err
+		}
+
+		// Only forget the domain once the record has actually been cleared, so a failed cleanup
+		// can be retried.
+		d.pendingMu.Lock()
+		delete(d.pending, mainDomain)
+		d.pendingMu.Unlock()
+
+		return nil
+	}
+
+	txt, err := challenge.txtValue(d.config.Verbose)
+	if err != nil {
+		return fmt.Errorf("duckdns: %s: %w", mainDomain, err)
+	}
+
+	return d.updateTxtRecord(mainDomain, txt, false)
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation with DuckDNS.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+func (d *DNSProvider) getOrCreatePending(mainDomain string) *pendingChallenge {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	challenge, ok := d.pending[mainDomain]
+	if !ok {
+		challenge = &pendingChallenge{counts: make(map[string]int)}
+		d.pending[mainDomain] = challenge
+	}
+
+	return challenge
+}
+
+// rollbackPending undoes a Present's add of value, so a failed Present (which lego will never
+// call CleanUp for) doesn't leave a phantom value that pollutes every future call for mainDomain.
+func (d *DNSProvider) rollbackPending(mainDomain string, challenge *pendingChallenge, value string) {
+	challenge.remove(value)
+
+	if len(challenge.order) == 0 {
+		d.pendingMu.Lock()
+		delete(d.pending, mainDomain)
+		d.pendingMu.Unlock()
+	}
+}
+
+// domainLock returns the mutex serializing Present/CleanUp calls for the given main domain,
+// creating it if necessary.
+func (d *DNSProvider) domainLock(mainDomain string) *sync.Mutex {
+	d.domainLocksMu.Lock()
+	defer d.domainLocksMu.Unlock()
+
+	mu, ok := d.domainLocks[mainDomain]
+	if !ok {
+		mu = &sync.Mutex{}
+		d.domainLocks[mainDomain] = mu
+	}
+
+	return mu
+}
+
+func (d *DNSProvider) updateTxtRecord(mainDomain, txt string, clear bool) error {
+	reqURL, err := url.Parse(d.config.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	query := reqURL.Query()
+	query.Set("domains", mainDomain)
+	query.Set("token", d.config.Token)
+	query.Set("txt", txt)
+
+	if clear {
+		query.Set("clear", "true")
+	}
+
+	if d.config.Verbose {
+		query.Set("verbose", "true")
+	}
+
+	reqURL.RawQuery = query.Encode()
+
+	resp, err := d.config.HTTPClient.Get(reqURL.String())
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	body := strings.TrimSpace(string(rawBody))
+	if !strings.HasPrefix(body, "OK") {
+		return fmt.Errorf("request to change TXT record for DuckDNS returned %q", body)
+	}
+
+	return nil
+}
+
+// getMainDomain extracts the domain registered with DuckDNS from an effective FQDN used for the
+// _acme-challenge TXT record, e.g. "_acme-challenge.foo.bar.duckdns.org." -> "bar.duckdns.org".
+func getMainDomain(fqdn string) string {
+	unFQDN := dns01.UnFqdn(fqdn)
+
+	rest := strings.TrimPrefix(unFQDN, challengePrefix)
+	if rest == unFQDN {
+		return ""
+	}
+
+	if strings.HasSuffix(rest, mainDomainSuffix) {
+		labels := strings.Split(strings.TrimSuffix(rest, mainDomainSuffix), ".")
+		return labels[len(labels)-1] + mainDomainSuffix
+	}
+
+	labels := strings.Split(rest, ".")
+
+	return labels[len(labels)-1]
+}
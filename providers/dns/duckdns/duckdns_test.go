@@ -1,9 +1,14 @@
 package duckdns
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -148,6 +153,73 @@ func Test_getMainDomain(t *testing.T) {
 	}
 }
 
+func mockDuckDNSServer(t *testing.T) (*Config, *[]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var txtValues []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		txtValues = append(txtValues, req.URL.Query().Get("txt"))
+		mu.Unlock()
+
+		_, _ = rw.Write([]byte("OK"))
+	}))
+	t.Cleanup(server.Close)
+
+	config := NewDefaultConfig()
+	config.BaseURL = server.URL
+	config.Token = "123"
+
+	return config, &txtValues
+}
+
+func TestDNSProvider_Present_concurrentWildcardAndApex(t *testing.T) {
+	config, _ := mockDuckDNSServer(t)
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.duckdns.org", "", "apex-key-auth")
+	require.NoError(t, err)
+
+	err = p.Present("*.example.duckdns.org", "", "wildcard-key-auth")
+	require.Error(t, err, "DuckDNS only stores a single TXT value, so a second distinct value must fail without the verbose workaround")
+
+	err = p.CleanUp("*.example.duckdns.org", "", "wildcard-key-auth")
+	require.NoError(t, err)
+
+	err = p.CleanUp("example.duckdns.org", "", "apex-key-auth")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_Present_concurrentWildcardAndApex_verbose(t *testing.T) {
+	config, txtValues := mockDuckDNSServer(t)
+	config.Verbose = true
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.duckdns.org", "", "apex-key-auth")
+	require.NoError(t, err)
+
+	err = p.Present("*.example.duckdns.org", "", "wildcard-key-auth")
+	require.NoError(t, err)
+
+	apexInfo := dns01.GetChallengeInfo("example.duckdns.org", "apex-key-auth")
+	wildcardInfo := dns01.GetChallengeInfo("*.example.duckdns.org", "wildcard-key-auth")
+
+	last := (*txtValues)[len(*txtValues)-1]
+	assert.ElementsMatch(t, []string{apexInfo.Value, wildcardInfo.Value}, strings.Split(last, ","))
+
+	err = p.CleanUp("*.example.duckdns.org", "", "wildcard-key-auth")
+	require.NoError(t, err)
+
+	err = p.CleanUp("example.duckdns.org", "", "apex-key-auth")
+	require.NoError(t, err)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -2,30 +2,36 @@
 package gandi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/dns/gandi/internal"
 )
 
-// Gandi API reference:       http://doc.rpc.gandi.net/index.html
-// Gandi API domain examples: http://doc.rpc.gandi.net/domain/faq.html
+// Gandi LiveDNS API reference: https://api.gandi.net/docs/livedns/
 
 const (
-	// defaultBaseURL Gandi XML-RPC endpoint used by Present and CleanUp.
-	defaultBaseURL = "https://rpc.gandi.net/xmlrpc/"
-	minTTL         = 300
+	minTTL = 300
+
+	defaultLiveDNSBaseURL = "https://api.gandi.net/v5/livedns"
+
+	// defaultLegacyBaseURL is Gandi's deprecated XML-RPC endpoint, kept only for Config.Legacy users.
+	defaultLegacyBaseURL = "https://rpc.gandi.net/xmlrpc/"
 )
 
 // Environment variables names.
 const (
 	envNamespace = "GANDI_"
 
-	EnvAPIKey = envNamespace + "API_KEY"
+	EnvAPIKey              = envNamespace + "API_KEY"
+	EnvPersonalAccessToken = envNamespace + "PERSONAL_ACCESS_TOKEN"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -35,8 +41,22 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	BaseURL            string
-	APIKey             string
+	// BaseURL overrides the default API endpoint (LiveDNS, or the legacy XML-RPC endpoint when Legacy is set).
+	BaseURL string
+
+	// PersonalAccessToken authenticates against the LiveDNS API as a Bearer token.
+	// It takes priority over APIKey when both are set.
+	PersonalAccessToken string
+
+	// APIKey authenticates against the LiveDNS API (via X-Api-Key) or, when Legacy is set, the XML-RPC API.
+	APIKey string
+
+	// Legacy selects the deprecated XML-RPC zone-clone workflow instead of LiveDNS.
+	// It exists only to give users still on classic (non-LiveDNS) zones a release cycle to migrate.
+	//
+	// Deprecated: will be removed in a future release.
+	Legacy bool
+
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int
@@ -55,8 +75,25 @@ func NewDefaultConfig() *Config {
 	}
 }
 
-// inProgressInfo contains information about an in-progress challenge.
-type inProgressInfo struct {
+// pendingRecord tracks the TXT values currently requested for a single rrset,
+// so that concurrent challenges under the same name (e.g. wildcard + apex) can coexist.
+type pendingRecord struct {
+	counts map[string]int
+}
+
+func (r *pendingRecord) values() []string {
+	values := make([]string, 0, len(r.counts))
+	for value := range r.counts {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	return values
+}
+
+// legacyInfo contains information about an in-progress legacy challenge.
+type legacyInfo struct {
 	zoneID    int    // zoneID of gandi zone to restore in CleanUp
 	newZoneID int    // zoneID of temporary gandi zone containing TXT record
 	authZone  string // the domain name registered at gandi with trailing "."
@@ -64,23 +101,42 @@ type inProgressInfo struct {
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
-	inProgressFQDNs     map[string]inProgressInfo
-	inProgressAuthZones map[string]struct{}
-	inProgressMu        sync.Mutex
-	config              *Config
+	config *Config
+
+	client       *internal.Client
+	legacyClient *internal.LegacyClient
+
+	// recordLocks serializes Present/CleanUp per rrset (subDomain+"@"+fqdn), so that concurrent
+	// challenges on unrelated domains or zones don't block on each other's outbound HTTP calls.
+	recordLocksMu sync.Mutex
+	recordLocks   map[string]*sync.Mutex
+
+	// pendingRecordsMu guards only the pendingRecords map itself; once a *pendingRecord is
+	// obtained, access to it is already serialized by the matching recordLocks entry.
+	pendingRecordsMu sync.Mutex
+	pendingRecords   map[string]*pendingRecord
+
+	legacyMu                  sync.Mutex
+	legacyInProgressFQDNs     map[string]legacyInfo
+	legacyInProgressAuthZones map[string]struct{}
+
 	// findZoneByFqdn determines the DNS zone of an fqdn. It is overridden during tests.
 	findZoneByFqdn func(fqdn string) (string, error)
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Gandi.
-// Credentials must be passed in the environment variable: GANDI_API_KEY.
+// Credentials must be passed in the environment variable GANDI_PERSONAL_ACCESS_TOKEN,
+// or GANDI_API_KEY (sent to LiveDNS via the X-Api-Key header).
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIKey)
+	values, err := env.GetWithFallback(
+		[]string{EnvPersonalAccessToken, EnvAPIKey},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("gandi: %w", err)
 	}
 
 	config := NewDefaultConfig()
+	config.PersonalAccessToken = values[EnvPersonalAccessToken]
 	config.APIKey = values[EnvAPIKey]
 
 	return NewDNSProviderConfig(config)
@@ -92,130 +148,201 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("gandi: the configuration of the DNS provider is nil")
 	}
 
+	if config.Legacy {
+		return newLegacyDNSProvider(config)
+	}
+
+	if config.PersonalAccessToken == "" && config.APIKey == "" {
+		return nil, errors.New("gandi: missing credentials: PersonalAccessToken or APIKey")
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = defaultLiveDNSBaseURL
+	}
+
+	client := internal.NewClient(config.BaseURL, config.HTTPClient)
+	client.PersonalAccessToken = config.PersonalAccessToken
+	client.APIKey = config.APIKey
+
+	return &DNSProvider{
+		config:         config,
+		client:         client,
+		recordLocks:    make(map[string]*sync.Mutex),
+		pendingRecords: make(map[string]*pendingRecord),
+		findZoneByFqdn: dns01.FindZoneByFqdn,
+	}, nil
+}
+
+func newLegacyDNSProvider(config *Config) (*DNSProvider, error) {
 	if config.APIKey == "" {
 		return nil, errors.New("gandi: no API Key given")
 	}
 
 	if config.BaseURL == "" {
-		config.BaseURL = defaultBaseURL
+		config.BaseURL = defaultLegacyBaseURL
 	}
 
 	return &DNSProvider{
-		config:              config,
-		inProgressFQDNs:     make(map[string]inProgressInfo),
-		inProgressAuthZones: make(map[string]struct{}),
-		findZoneByFqdn:      dns01.FindZoneByFqdn,
+		config:                    config,
+		legacyClient:              internal.NewLegacyClient(config.APIKey, config.BaseURL, config.HTTPClient),
+		legacyInProgressFQDNs:     make(map[string]legacyInfo),
+		legacyInProgressAuthZones: make(map[string]struct{}),
+		findZoneByFqdn:            dns01.FindZoneByFqdn,
 	}, nil
 }
 
-// Present creates a TXT record using the specified parameters. It
-// does this by creating and activating a new temporary Gandi DNS
-// zone. This new zone contains the TXT record.
+// Present creates or updates the TXT rrset for the given challenge,
+// merging in any other TXT value already requested for the same rrset (e.g. by a concurrent wildcard + apex order).
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	if d.config.TTL < minTTL {
-		d.config.TTL = minTTL // 300 is gandi minimum value for ttl
+	if d.config.Legacy {
+		return d.presentLegacy(domain, token, keyAuth)
 	}
 
-	// find authZone and Gandi zone_id for fqdn
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
 	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("gandi: findZoneByFqdn failure: %w", err)
 	}
 
-	zoneID, err := d.getZoneID(authZone)
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
 	if err != nil {
 		return fmt.Errorf("gandi: %w", err)
 	}
 
-	// determine name of TXT record
-	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	fqdn := dns01.UnFqdn(authZone)
+	key := subDomain + "@" + fqdn
+
+	ttl := d.config.TTL
+	if ttl < minTTL {
+		ttl = minTTL // 300 is gandi minimum value for ttl
+	}
+
+	mu := d.recordLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	record := d.getOrCreatePendingRecord(key)
+	record.counts[info.Value]++
+
+	err = d.client.UpdateTXTRecord(context.Background(), fqdn, subDomain, ttl, record.values())
 	if err != nil {
+		d.forgetPendingValue(key, record, info.Value)
 		return fmt.Errorf("gandi: %w", err)
 	}
 
-	// acquire lock and check there is not a challenge already in
-	// progress for this value of authZone
-	d.inProgressMu.Lock()
-	defer d.inProgressMu.Unlock()
+	return nil
+}
 
-	if _, ok := d.inProgressAuthZones[authZone]; ok {
-		return fmt.Errorf("gandi: challenge already in progress for authZone %s", authZone)
+// CleanUp removes this challenge's TXT value from the rrset,
+// deleting the rrset entirely once no challenge still needs it.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	if d.config.Legacy {
+		return d.cleanUpLegacy(domain, token, keyAuth)
 	}
 
-	// perform API actions to create and activate new gandi zone
-	// containing the required TXT record
-	newZoneName := fmt.Sprintf("%s [ACME Challenge %s]", dns01.UnFqdn(authZone), time.Now().Format(time.RFC822Z))
+	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	newZoneID, err := d.cloneZone(zoneID, newZoneName)
+	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
-		return err
+		return fmt.Errorf("gandi: findZoneByFqdn failure: %w", err)
 	}
 
-	newZoneVersion, err := d.newZoneVersion(newZoneID)
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
 	if err != nil {
 		return fmt.Errorf("gandi: %w", err)
 	}
 
-	err = d.addTXTRecord(newZoneID, newZoneVersion, subDomain, info.Value, d.config.TTL)
-	if err != nil {
-		return fmt.Errorf("gandi: %w", err)
+	fqdn := dns01.UnFqdn(authZone)
+	key := subDomain + "@" + fqdn
+
+	mu := d.recordLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	d.pendingRecordsMu.Lock()
+	record, ok := d.pendingRecords[key]
+	d.pendingRecordsMu.Unlock()
+
+	if !ok {
+		return nil
 	}
 
-	err = d.setZoneVersion(newZoneID, newZoneVersion)
-	if err != nil {
-		return fmt.Errorf("gandi: %w", err)
+	record.counts[info.Value]--
+	if record.counts[info.Value] <= 0 {
+		delete(record.counts, info.Value)
 	}
 
-	err = d.setZone(authZone, newZoneID)
-	if err != nil {
-		return fmt.Errorf("gandi: %w", err)
+	if len(record.counts) == 0 {
+		if err := d.client.DeleteTXTRecord(context.Background(), fqdn, subDomain); err != nil {
+			return fmt.Errorf("gandi: %w", err)
+		}
+
+		// Only forget the rrset once it has actually been removed, so a failed cleanup can be retried.
+		d.pendingRecordsMu.Lock()
+		delete(d.pendingRecords, key)
+		d.pendingRecordsMu.Unlock()
+
+		return nil
 	}
 
-	// save data necessary for CleanUp
-	d.inProgressFQDNs[info.EffectiveFQDN] = inProgressInfo{
-		zoneID:    zoneID,
-		newZoneID: newZoneID,
-		authZone:  authZone,
+	ttl := d.config.TTL
+	if ttl < minTTL {
+		ttl = minTTL
+	}
+
+	if err := d.client.UpdateTXTRecord(context.Background(), fqdn, subDomain, ttl, record.values()); err != nil {
+		return fmt.Errorf("gandi: %w", err)
 	}
-	d.inProgressAuthZones[authZone] = struct{}{}
 
 	return nil
 }
 
-// CleanUp removes the TXT record matching the specified
-// parameters. It does this by restoring the old Gandi DNS zone and
-// removing the temporary one created by Present.
-func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+func (d *DNSProvider) getOrCreatePendingRecord(key string) *pendingRecord {
+	d.pendingRecordsMu.Lock()
+	defer d.pendingRecordsMu.Unlock()
 
-	// acquire lock and retrieve zoneID, newZoneID and authZone
-	d.inProgressMu.Lock()
-	defer d.inProgressMu.Unlock()
+	record, ok := d.pendingRecords[key]
+	if !ok {
+		record = &pendingRecord{counts: make(map[string]int)}
+		d.pendingRecords[key] = record
+	}
 
-	if _, ok := d.inProgressFQDNs[info.EffectiveFQDN]; !ok {
-		// if there is no cleanup information then just return
-		return nil
+	return record
+}
+
+// forgetPendingValue undoes a Present's increment of value, so a failed Present (which lego will
+// never call CleanUp for) doesn't leave a phantom value that gets resubmitted on every future call
+// for key.
+func (d *DNSProvider) forgetPendingValue(key string, record *pendingRecord, value string) {
+	record.counts[value]--
+	if record.counts[value] <= 0 {
+		delete(record.counts, value)
 	}
 
-	zoneID := d.inProgressFQDNs[info.EffectiveFQDN].zoneID
-	newZoneID := d.inProgressFQDNs[info.EffectiveFQDN].newZoneID
-	authZone := d.inProgressFQDNs[info.EffectiveFQDN].authZone
-	delete(d.inProgressFQDNs, info.EffectiveFQDN)
-	delete(d.inProgressAuthZones, authZone)
+	if len(record.counts) == 0 {
+		d.pendingRecordsMu.Lock()
+		delete(d.pendingRecords, key)
+		d.pendingRecordsMu.Unlock()
+	}
+}
 
-	// perform API actions to restore old gandi zone for authZone
-	err := d.setZone(authZone, zoneID)
-	if err != nil {
-		return fmt.Errorf("gandi: %w", err)
+// recordLock returns the mutex serializing Present/CleanUp calls for the given rrset key
+// (subDomain+"@"+fqdn), creating it if necessary.
+func (d *DNSProvider) recordLock(key string) *sync.Mutex {
+	d.recordLocksMu.Lock()
+	defer d.recordLocksMu.Unlock()
+
+	mu, ok := d.recordLocks[key]
+	if !ok {
+		mu = &sync.Mutex{}
+		d.recordLocks[key] = mu
 	}
 
-	return d.deleteZone(newZoneID)
+	return mu
 }
 
-// Timeout returns the values (40*time.Minute, 60*time.Second) which
-// are used by the acme package as timeout and check interval values
+// Timeout returns the values used by the acme package as timeout and check interval values
 // when checking for DNS record propagation with Gandi.
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
@@ -0,0 +1,109 @@
+package gandi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// presentLegacy creates a TXT record using the deprecated XML-RPC API. It does this by cloning
+// the current zone, editing the TXT record into the new version, and swapping it in.
+//
+// Deprecated: kept only for Config.Legacy users still on classic (non-LiveDNS) zones.
+func (d *DNSProvider) presentLegacy(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	if d.config.TTL < minTTL {
+		d.config.TTL = minTTL // 300 is gandi minimum value for ttl
+	}
+
+	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("gandi: findZoneByFqdn failure: %w", err)
+	}
+
+	zoneID, err := d.legacyClient.GetZoneID(dns01.UnFqdn(authZone))
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	d.legacyMu.Lock()
+	defer d.legacyMu.Unlock()
+
+	if _, ok := d.legacyInProgressAuthZones[authZone]; ok {
+		return fmt.Errorf("gandi: challenge already in progress for authZone %s", authZone)
+	}
+
+	newZoneName := fmt.Sprintf("%s [ACME Challenge %s]", dns01.UnFqdn(authZone), time.Now().Format(time.RFC822Z))
+
+	newZoneID, err := d.legacyClient.CloneZone(zoneID, newZoneName)
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	newZoneVersion, err := d.legacyClient.NewZoneVersion(newZoneID)
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	err = d.legacyClient.AddTXTRecord(newZoneID, newZoneVersion, subDomain, info.Value, d.config.TTL)
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	err = d.legacyClient.SetZoneVersion(newZoneID, newZoneVersion)
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	err = d.legacyClient.SetZone(dns01.UnFqdn(authZone), newZoneID)
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	d.legacyInProgressFQDNs[info.EffectiveFQDN] = legacyInfo{
+		zoneID:    zoneID,
+		newZoneID: newZoneID,
+		authZone:  authZone,
+	}
+	d.legacyInProgressAuthZones[authZone] = struct{}{}
+
+	return nil
+}
+
+// cleanUpLegacy removes the TXT record matching the specified parameters by restoring the old
+// Gandi DNS zone and removing the temporary one created by presentLegacy.
+//
+// Deprecated: kept only for Config.Legacy users still on classic (non-LiveDNS) zones.
+func (d *DNSProvider) cleanUpLegacy(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.legacyMu.Lock()
+	defer d.legacyMu.Unlock()
+
+	progress, ok := d.legacyInProgressFQDNs[info.EffectiveFQDN]
+	if !ok {
+		// if there is no cleanup information then just return
+		return nil
+	}
+
+	delete(d.legacyInProgressFQDNs, info.EffectiveFQDN)
+	delete(d.legacyInProgressAuthZones, progress.authZone)
+
+	err := d.legacyClient.SetZone(dns01.UnFqdn(progress.authZone), progress.zoneID)
+	if err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	if err := d.legacyClient.DeleteZone(progress.newZoneID); err != nil {
+		return fmt.Errorf("gandi: %w", err)
+	}
+
+	return nil
+}
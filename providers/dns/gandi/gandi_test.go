@@ -0,0 +1,219 @@
+package gandi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvPersonalAccessToken, EnvAPIKey).
+	WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		envVars     map[string]string
+		expectError bool
+	}{
+		{
+			desc: "success with personal access token",
+			envVars: map[string]string{
+				EnvPersonalAccessToken: "123",
+			},
+		},
+		{
+			desc: "success with legacy API key",
+			envVars: map[string]string{
+				EnvAPIKey: "123",
+			},
+		},
+		{
+			desc:        "missing credentials",
+			envVars:     map[string]string{},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, p)
+			require.NotNil(t, p.config)
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		personalAccessToken string
+		apiKey              string
+		legacy              bool
+		expected            string
+	}{
+		{
+			desc:                "success: personal access token",
+			personalAccessToken: "123",
+		},
+		{
+			desc:   "success: API key",
+			apiKey: "123",
+		},
+		{
+			desc:     "missing credentials",
+			expected: "gandi: missing credentials: PersonalAccessToken or APIKey",
+		},
+		{
+			desc:   "success: legacy",
+			apiKey: "123",
+			legacy: true,
+		},
+		{
+			desc:     "legacy: missing API key",
+			legacy:   true,
+			expected: "gandi: no API Key given",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.PersonalAccessToken = test.personalAccessToken
+			config.APIKey = test.apiKey
+			config.Legacy = test.legacy
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+
+				if test.legacy {
+					assert.NotNil(t, p.legacyClient)
+					assert.Nil(t, p.client)
+				} else {
+					assert.NotNil(t, p.client)
+					assert.Nil(t, p.legacyClient)
+				}
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+// fakeLiveDNSAPI is a minimal Gandi LiveDNS REST API double, recording the rrset_values sent on
+// every PUT and the number of DELETE calls made.
+type fakeLiveDNSAPI struct {
+	lastValues []string
+	deletes    int
+}
+
+func (f *fakeLiveDNSAPI) handler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPut:
+			var body struct {
+				RRSetTTL    int      `json:"rrset_ttl"`
+				RRSetValues []string `json:"rrset_values"`
+			}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+
+			sort.Strings(body.RRSetValues)
+			f.lastValues = body.RRSetValues
+
+			rw.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			f.deletes++
+			rw.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected method: %s", req.Method)
+		}
+	}
+}
+
+func TestDNSProvider_Present_concurrentWildcardAndApex(t *testing.T) {
+	api := &fakeLiveDNSAPI{}
+	server := httptest.NewServer(api.handler(t))
+	t.Cleanup(server.Close)
+
+	config := NewDefaultConfig()
+	config.PersonalAccessToken = "123"
+	config.BaseURL = server.URL
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	provider.findZoneByFqdn = func(string) (string, error) {
+		return "example.com.", nil
+	}
+
+	// lego's PreSolve calls Present(authz.Identifier.Value, ...): that identifier never carries a
+	// "*." prefix, even for a wildcard authorization, so the apex and wildcard challenges for the
+	// same base domain are presented with the identical domain string and differ only in keyAuth.
+	apexInfo := dns01.GetChallengeInfo("example.com", "apex-key-auth")
+	wildcardInfo := dns01.GetChallengeInfo("example.com", "wildcard-key-auth")
+
+	require.NoError(t, provider.Present("example.com", "", "apex-key-auth"))
+	assert.ElementsMatch(t, []string{apexInfo.Value}, api.lastValues)
+
+	require.NoError(t, provider.Present("example.com", "", "wildcard-key-auth"))
+	assert.ElementsMatch(t, []string{apexInfo.Value, wildcardInfo.Value}, api.lastValues)
+
+	require.NoError(t, provider.CleanUp("example.com", "", "wildcard-key-auth"))
+	assert.ElementsMatch(t, []string{apexInfo.Value}, api.lastValues)
+	assert.Equal(t, 0, api.deletes)
+
+	require.NoError(t, provider.CleanUp("example.com", "", "apex-key-auth"))
+	assert.Equal(t, 1, api.deletes)
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
@@ -0,0 +1,145 @@
+// Package internal implements a client for the Gandi LiveDNS REST API.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const defaultBaseURL = "https://api.gandi.net/v5/livedns"
+
+// Client is a client for the Gandi LiveDNS REST API.
+type Client struct {
+	// PersonalAccessToken, when set, is sent as a Bearer token and takes priority over APIKey.
+	PersonalAccessToken string
+	// APIKey is sent via the legacy X-Api-Key header.
+	APIKey string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	apiURL, err := url.Parse(baseURL)
+	if err != nil {
+		apiURL, _ = url.Parse(defaultBaseURL)
+	}
+
+	return &Client{baseURL: apiURL, HTTPClient: httpClient}
+}
+
+type txtRecord struct {
+	RRSetTTL    int      `json:"rrset_ttl,omitempty"`
+	RRSetValues []string `json:"rrset_values"`
+}
+
+type apiError struct {
+	Message string `json:"message"`
+	Cause   string `json:"cause"`
+	Code    int    `json:"code"`
+}
+
+func (a apiError) Error() string {
+	return fmt.Sprintf("%d: %s: %s", a.Code, a.Cause, a.Message)
+}
+
+// UpdateTXTRecord creates or replaces the TXT rrset named rrsetName under fqdn with values.
+func (c *Client) UpdateTXTRecord(ctx context.Context, fqdn, rrsetName string, ttl int, values []string) error {
+	endpoint := c.baseURL.JoinPath("domains", fqdn, "records", rrsetName, "TXT")
+
+	req, err := newJSONRequest(ctx, http.MethodPut, endpoint, txtRecord{RRSetTTL: ttl, RRSetValues: values})
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
+
+// DeleteTXTRecord deletes the TXT rrset named rrsetName under fqdn.
+func (c *Client) DeleteTXTRecord(ctx context.Context, fqdn, rrsetName string) error {
+	endpoint := c.baseURL.JoinPath("domains", fqdn, "records", rrsetName, "TXT")
+
+	req, err := newJSONRequest(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
+
+func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
+	var body io.Reader
+
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	switch {
+	case c.PersonalAccessToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.PersonalAccessToken)
+	case c.APIKey != "":
+		req.Header.Set("X-Api-Key", c.APIKey)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr apiError
+		if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr == nil && apiErr.Message != "" {
+			return apiErr
+		}
+
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(raw))
+	}
+
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_UpdateTXTRecord_auth(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		personalAccessToken string
+		apiKey              string
+		expectedHeader      string
+		expectedValue       string
+	}{
+		{
+			desc:                "personal access token takes priority",
+			personalAccessToken: "pat-secret",
+			apiKey:              "key-secret",
+			expectedHeader:      "Authorization",
+			expectedValue:       "Bearer pat-secret",
+		},
+		{
+			desc:           "API key only",
+			apiKey:         "key-secret",
+			expectedHeader: "X-Api-Key",
+			expectedValue:  "key-secret",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var gotHeader http.Header
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/domains/example.com/records/_acme-challenge/TXT", func(rw http.ResponseWriter, req *http.Request) {
+				gotHeader = req.Header
+				rw.WriteHeader(http.StatusCreated)
+			})
+
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			client := NewClient(server.URL, server.Client())
+			client.PersonalAccessToken = test.personalAccessToken
+			client.APIKey = test.apiKey
+
+			err := client.UpdateTXTRecord(context.Background(), "example.com", "_acme-challenge", 300, []string{"value"})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedValue, gotHeader.Get(test.expectedHeader))
+		})
+	}
+}
+
+func TestClient_UpdateTXTRecord_error(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/domains/example.com/records/_acme-challenge/TXT", func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+		_, _ = rw.Write([]byte(`{"cause":"Forbidden","code":401,"message":"The server is not able to authenticate your request."}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, server.Client())
+	client.APIKey = "key-secret"
+
+	err := client.UpdateTXTRecord(context.Background(), "example.com", "_acme-challenge", 300, []string{"value"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "The server is not able to authenticate your request.")
+}
+
+func TestClient_DeleteTXTRecord(t *testing.T) {
+	var gotMethod string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/domains/example.com/records/_acme-challenge/TXT", func(rw http.ResponseWriter, req *http.Request) {
+		gotMethod = req.Method
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, server.Client())
+
+	err := client.DeleteTXTRecord(context.Background(), "example.com", "_acme-challenge")
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodDelete, gotMethod)
+}
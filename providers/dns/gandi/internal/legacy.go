@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LegacyClient is a minimal XML-RPC client for the deprecated Gandi zone API.
+//
+// Deprecated: Gandi's XML-RPC endpoint is scheduled for removal; this client only exists to give
+// users still on classic (non-LiveDNS) zones a release cycle to migrate.
+type LegacyClient struct {
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewLegacyClient creates a new LegacyClient.
+func NewLegacyClient(apiKey, baseURL string, httpClient *http.Client) *LegacyClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &LegacyClient{APIKey: apiKey, BaseURL: baseURL, HTTPClient: httpClient}
+}
+
+// GetZoneID returns the zone_id currently attached to a domain.
+func (c *LegacyClient) GetZoneID(domain string) (int, error) {
+	resp, err := c.call("domain.info", param{str: c.APIKey}, param{str: domain})
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.memberInt("zone_id")
+}
+
+// CloneZone creates a new zone, cloned from zoneID, with the given name, and returns its ID.
+func (c *LegacyClient) CloneZone(zoneID int, name string) (int, error) {
+	resp, err := c.call("domain.zone.clone", param{str: c.APIKey}, param{i: &zoneID}, param{i: new(int)}, param{str: name})
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.asInt()
+}
+
+// NewZoneVersion creates a new version of zoneID and returns its version number.
+func (c *LegacyClient) NewZoneVersion(zoneID int) (int, error) {
+	resp, err := c.call("domain.zone.version.new", param{str: c.APIKey}, param{i: &zoneID})
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.asInt()
+}
+
+// AddTXTRecord adds a TXT record to the given zone version.
+func (c *LegacyClient) AddTXTRecord(zoneID, version int, name, value string, ttl int) error {
+	_, err := c.call("domain.zone.record.add", param{str: c.APIKey}, param{i: &zoneID}, param{i: &version},
+		param{record: &txtFieldset{Type: "TXT", Name: name, Value: strconv.Quote(value), TTL: ttl}})
+
+	return err
+}
+
+// SetZoneVersion activates version on zoneID.
+func (c *LegacyClient) SetZoneVersion(zoneID, version int) error {
+	_, err := c.call("domain.zone.version.set", param{str: c.APIKey}, param{i: &zoneID}, param{i: &version})
+
+	return err
+}
+
+// SetZone attaches zoneID to domain.
+func (c *LegacyClient) SetZone(domain string, zoneID int) error {
+	_, err := c.call("domain.zone.set", param{str: c.APIKey}, param{str: domain}, param{i: &zoneID})
+
+	return err
+}
+
+// DeleteZone deletes zoneID.
+func (c *LegacyClient) DeleteZone(zoneID int) error {
+	_, err := c.call("domain.zone.delete", param{str: c.APIKey}, param{i: &zoneID})
+
+	return err
+}
+
+// param is a tagged union covering the handful of XML-RPC value shapes this legacy client sends.
+type param struct {
+	str    string
+	i      *int
+	record *txtFieldset
+}
+
+type txtFieldset struct {
+	Type  string
+	Name  string
+	Value string
+	TTL   int
+}
+
+func (p param) xmlValue() string {
+	switch {
+	case p.i != nil:
+		return fmt.Sprintf("<value><int>%d</int></value>", *p.i)
+	case p.record != nil:
+		return fmt.Sprintf(`<value><struct>
+			<member><name>type</name><value><string>%s</string></value></member>
+			<member><name>name</name><value><string>%s</string></value></member>
+			<member><name>value</name><value><string>%s</string></value></member>
+			<member><name>ttl</name><value><int>%d</int></value></member>
+		</struct></value>`, p.record.Type, xmlEscape(p.record.Name), xmlEscape(p.record.Value), p.record.TTL)
+	default:
+		return fmt.Sprintf("<value><string>%s</string></value>", xmlEscape(p.str))
+	}
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
+
+// call performs a single XML-RPC request and returns the decoded first return value.
+func (c *LegacyClient) call(method string, params ...param) (rpcValue, error) {
+	var body bytes.Buffer
+
+	body.WriteString("<?xml version=\"1.0\"?><methodCall><methodName>")
+	body.WriteString(method)
+	body.WriteString("</methodName><params>")
+
+	for _, p := range params {
+		body.WriteString("<param>")
+		body.WriteString(p.xmlValue())
+		body.WriteString("</param>")
+	}
+
+	body.WriteString("</params></methodCall>")
+
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return rpcValue{}, fmt.Errorf("xmlrpc: failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "text/xml")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return rpcValue{}, fmt.Errorf("xmlrpc: %s: %w", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return rpcValue{}, fmt.Errorf("xmlrpc: %s: failed to read response: %w", method, err)
+	}
+
+	var methodResponse struct {
+		Fault *rpcValue `xml:"fault>value"`
+		Param *rpcValue `xml:"params>param>value"`
+	}
+
+	if err := xml.Unmarshal(raw, &methodResponse); err != nil {
+		return rpcValue{}, fmt.Errorf("xmlrpc: %s: failed to parse response: %w", method, err)
+	}
+
+	if methodResponse.Fault != nil {
+		faultString, _ := methodResponse.Fault.memberString("faultString")
+		return rpcValue{}, fmt.Errorf("xmlrpc: %s: %s", method, faultString)
+	}
+
+	if methodResponse.Param == nil {
+		return rpcValue{}, nil
+	}
+
+	return *methodResponse.Param, nil
+}
+
+// rpcValue decodes the handful of XML-RPC response shapes this client reads back.
+type rpcValue struct {
+	Int    *int   `xml:"int"`
+	String string `xml:"string"`
+	Struct []struct {
+		Name  string   `xml:"name"`
+		Value rpcValue `xml:"value"`
+	} `xml:"struct>member"`
+}
+
+func (v rpcValue) asInt() (int, error) {
+	if v.Int == nil {
+		return 0, fmt.Errorf("xmlrpc: expected an int response, got %q", v.String)
+	}
+
+	return *v.Int, nil
+}
+
+func (v rpcValue) memberInt(name string) (int, error) {
+	for _, m := range v.Struct {
+		if m.Name == name {
+			return m.Value.asInt()
+		}
+	}
+
+	return 0, fmt.Errorf("xmlrpc: missing struct member %q", name)
+}
+
+func (v rpcValue) memberString(name string) (string, error) {
+	for _, m := range v.Struct {
+		if m.Name == name {
+			return m.Value.String, nil
+		}
+	}
+
+	return "", fmt.Errorf("xmlrpc: missing struct member %q", name)
+}
@@ -0,0 +1,248 @@
+// Package hostingde implements a DNS provider for solving the DNS-01 challenge using hosting.de.
+package hostingde
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/dns/hostingde/internal"
+)
+
+// hosting.de API reference: https://www.hosting.de/api/#dns
+
+// Environment variables names.
+const (
+	envNamespace = "HOSTINGDE_"
+
+	EnvAPIKey   = envNamespace + "API_KEY"
+	EnvZoneName = envNamespace + "ZONE_NAME"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// maxRetries is the number of times a zoneUpdate call is retried after hosting.de reports a
+// "pending zone update" conflict before giving up.
+const maxRetries = 5
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	APIKey string
+	// ZoneName pins the provider to a single zone, skipping the DNS lookup of the zone from the
+	// challenge FQDN; zonesFind is still called with this name.
+	// Required when the zone cannot be derived from the domain being requested by that DNS lookup.
+	ZoneName string
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+
+	// zoneLocks serializes zoneUpdate calls per zone, since hosting.de rejects overlapping
+	// updates to the same zone with a "pending zone update" error.
+	zoneLocksMu sync.Mutex
+	zoneLocks   map[string]*sync.Mutex
+
+	// records correlates cleanup state by challenge value (info.Value), so that concurrent
+	// wildcard + apex challenges for the same domain (lego presents both under the identical,
+	// un-prefixed domain string) can each be torn down independently.
+	recordsMu sync.Mutex
+	records   map[string]internal.Record
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for hosting.de.
+// Credentials must be passed in the environment variable: HOSTINGDE_API_KEY.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvAPIKey)
+	if err != nil {
+		return nil, fmt.Errorf("hostingde: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.APIKey = values[EnvAPIKey]
+	config.ZoneName = env.GetOrDefaultString(EnvZoneName, "")
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for hosting.de.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("hostingde: the configuration of the DNS provider is nil")
+	}
+
+	if config.APIKey == "" {
+		return nil, errors.New("hostingde: no API Key given")
+	}
+
+	return &DNSProvider{
+		config:    config,
+		client:    internal.NewClient(config.APIKey, "", config.HTTPClient),
+		zoneLocks: make(map[string]*sync.Mutex),
+		records:   make(map[string]internal.Record),
+	}, nil
+}
+
+// Present creates a TXT record to fulfil the DNS-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zoneName, err := d.findZoneName(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("hostingde: %w", err)
+	}
+
+	recordName := dns01.UnFqdn(info.EffectiveFQDN)
+
+	return d.withZoneLock(zoneName, func() error {
+		zone, _, err := d.client.ZonesFind(context.Background(), zoneName)
+		if err != nil {
+			return fmt.Errorf("could not find zone %q: %w", zoneName, err)
+		}
+
+		record := internal.Record{
+			Type:    "TXT",
+			Name:    recordName,
+			Content: fmt.Sprintf("%q", info.Value),
+			TTL:     d.config.TTL,
+		}
+
+		if _, err := d.client.ZoneUpdate(context.Background(), zone, []internal.Record{record}, nil); err != nil {
+			return fmt.Errorf("could not add record to zone %q: %w", zoneName, err)
+		}
+
+		// Keyed by info.Value, not domain: lego presents both a wildcard and its apex challenge
+		// under the identical, un-prefixed domain string, so only the challenge value itself (and
+		// so the record content) tells the two records apart.
+		d.recordsMu.Lock()
+		d.records[info.Value] = record
+		d.recordsMu.Unlock()
+
+		return nil
+	})
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.recordsMu.Lock()
+	record, ok := d.records[info.Value]
+	d.recordsMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	zoneName, err := d.findZoneName(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("hostingde: %w", err)
+	}
+
+	err = d.withZoneLock(zoneName, func() error {
+		zone, _, err := d.client.ZonesFind(context.Background(), zoneName)
+		if err != nil {
+			return fmt.Errorf("could not find zone %q: %w", zoneName, err)
+		}
+
+		if _, err := d.client.ZoneUpdate(context.Background(), zone, nil, []internal.Record{record}); err != nil {
+			return fmt.Errorf("could not remove record from zone %q: %w", zoneName, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only forget the record once it has actually been removed, so a failed cleanup can be retried.
+	d.recordsMu.Lock()
+	delete(d.records, info.Value)
+	d.recordsMu.Unlock()
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation with hosting.de.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+func (d *DNSProvider) findZoneName(fqdn string) (string, error) {
+	if d.config.ZoneName != "" {
+		return d.config.ZoneName, nil
+	}
+
+	authZone, err := dns01.FindZoneByFqdn(fqdn)
+	if err != nil {
+		return "", fmt.Errorf("could not find zone for FQDN %q: %w", fqdn, err)
+	}
+
+	return dns01.UnFqdn(authZone), nil
+}
+
+// withZoneLock serializes fn against any other Present/CleanUp call for the same zone, then
+// retries it with a backoff while hosting.de reports the zone as still processing a prior update.
+func (d *DNSProvider) withZoneLock(zoneName string, fn func() error) error {
+	mu := d.zoneLock(zoneName)
+	mu.Lock()
+	defer mu.Unlock()
+
+	backoff := time.Second
+
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		err = fn()
+		if err == nil || !internal.IsPendingZoneUpdate(err) {
+			return err
+		}
+
+		if attempt == maxRetries {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return err
+}
+
+func (d *DNSProvider) zoneLock(zoneName string) *sync.Mutex {
+	d.zoneLocksMu.Lock()
+	defer d.zoneLocksMu.Unlock()
+
+	mu, ok := d.zoneLocks[zoneName]
+	if !ok {
+		mu = &sync.Mutex{}
+		d.zoneLocks[zoneName] = mu
+	}
+
+	return mu
+}
@@ -0,0 +1,217 @@
+package hostingde
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/providers/dns/hostingde/internal"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvAPIKey).
+	WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvAPIKey: "123",
+			},
+		},
+		{
+			desc: "missing api key",
+			envVars: map[string]string{
+				EnvAPIKey: "",
+			},
+			expected: "hostingde: some credentials information are missing: HOSTINGDE_API_KEY",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		apiKey   string
+		expected string
+	}{
+		{
+			desc:   "success",
+			apiKey: "123",
+		},
+		{
+			desc:     "missing credentials",
+			expected: "hostingde: no API Key given",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.APIKey = test.apiKey
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+// fakeAPI is a minimal hosting.de JSON API double, enough to drive zonesFind/zoneUpdate through
+// Present/CleanUp. It can be made to return a "pending zone update" conflict for a fixed number of
+// zoneUpdate calls before succeeding, to exercise the retry-with-backoff path.
+type fakeAPI struct {
+	pendingUpdatesLeft int
+}
+
+func (f *fakeAPI) handler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/zoneConfigsFind":
+			_ = json.NewEncoder(rw).Encode(map[string]any{
+				"status": "success",
+				"response": map[string]any{
+					"data": []map[string]any{
+						{
+							"zoneConfig": map[string]any{"id": "zone-1", "name": "example.com"},
+							"records":    []map[string]any{},
+						},
+					},
+				},
+			})
+		case "/zoneUpdate":
+			if f.pendingUpdatesLeft > 0 {
+				f.pendingUpdatesLeft--
+				_ = json.NewEncoder(rw).Encode(map[string]any{
+					"status": "error",
+					"errors": []map[string]any{
+						{"code": 8204, "text": "There is still a pending zone update for this zone."},
+					},
+				})
+				return
+			}
+
+			_ = json.NewEncoder(rw).Encode(map[string]any{
+				"status": "success",
+				"response": map[string]any{
+					"zoneConfig": map[string]any{"id": "zone-1", "name": "example.com"},
+				},
+			})
+		default:
+			t.Fatalf("unexpected request: %s", req.URL.Path)
+		}
+	}
+}
+
+func TestDNSProvider_Present_retriesOnPendingZoneUpdate(t *testing.T) {
+	api := &fakeAPI{pendingUpdatesLeft: 1}
+	server := httptest.NewServer(api.handler(t))
+	t.Cleanup(server.Close)
+
+	config := NewDefaultConfig()
+	config.APIKey = "123"
+	config.ZoneName = "example.com"
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	provider.client = internal.NewClient(config.APIKey, server.URL, nil)
+
+	err = provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+	require.Equal(t, 0, api.pendingUpdatesLeft)
+}
+
+func TestDNSProvider_Present_concurrentWildcardAndApex(t *testing.T) {
+	api := &fakeAPI{}
+	server := httptest.NewServer(api.handler(t))
+	t.Cleanup(server.Close)
+
+	config := NewDefaultConfig()
+	config.APIKey = "123"
+	config.ZoneName = "example.com"
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	provider.client = internal.NewClient(config.APIKey, server.URL, nil)
+
+	// lego's PreSolve calls Present with the bare domain for both the apex and wildcard challenge
+	// of an order (the identifier never carries a "*." prefix), so both calls below share the
+	// identical domain string and differ only in keyAuth.
+	errCh := make(chan error, 2)
+	go func() { errCh <- provider.Present("example.com", "", "apex-key-auth") }()
+	go func() { errCh <- provider.Present("example.com", "", "wildcard-key-auth") }()
+
+	require.NoError(t, <-errCh)
+	require.NoError(t, <-errCh)
+
+	require.Len(t, provider.records, 2, "apex and wildcard records must be tracked independently")
+
+	require.NoError(t, provider.CleanUp("example.com", "", "apex-key-auth"))
+	require.NoError(t, provider.CleanUp("example.com", "", "wildcard-key-auth"))
+
+	require.Empty(t, provider.records)
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
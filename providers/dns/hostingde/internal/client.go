@@ -0,0 +1,226 @@
+// Package internal implements a client for the hosting.de JSON API.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const defaultBaseURL = "https://secure.hosting.de/api/dns/v1/json/"
+
+// Client is a client for the hosting.de DNS API.
+type Client struct {
+	apiKey string
+
+	baseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(apiKey, baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{apiKey: apiKey, baseURL: baseURL, HTTPClient: httpClient}
+}
+
+// Record is a single DNS resource record, as returned by ZonesFind and used in ZoneUpdate's deltas.
+type Record struct {
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Content  string `json:"content"`
+	TTL      int    `json:"ttl,omitempty"`
+	ZoneName string `json:"zoneName,omitempty"`
+}
+
+// ZoneConfig is the subset of hosting.de's zoneConfigObject this provider reads and re-submits.
+type ZoneConfig struct {
+	ID           string `json:"id,omitempty"`
+	AccountID    string `json:"accountId,omitempty"`
+	Name         string `json:"name"`
+	NameUnicode  string `json:"nameUnicode,omitempty"`
+	MasterIP     string `json:"masterIp,omitempty"`
+	Type         string `json:"type,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+type findRequest struct {
+	AuthToken string     `json:"authToken"`
+	Filter    findFilter `json:"filter"`
+	Limit     int        `json:"limit"`
+	Page      int        `json:"page"`
+}
+
+type findFilter struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+type zoneConfigsFindResponse struct {
+	apiResponse
+	Response struct {
+		Data []struct {
+			Zone    ZoneConfig `json:"zoneConfig"`
+			Records []Record   `json:"records"`
+		} `json:"data"`
+	} `json:"response"`
+}
+
+type updateRequest struct {
+	AuthToken       string     `json:"authToken"`
+	ZoneConfig      ZoneConfig `json:"zoneConfig"`
+	RecordsToAdd    []Record   `json:"recordsToAdd,omitempty"`
+	RecordsToDelete []Record   `json:"recordsToDelete,omitempty"`
+}
+
+type zoneUpdateResponse struct {
+	apiResponse
+	Response struct {
+		Zone ZoneConfig `json:"zoneConfig"`
+	} `json:"response"`
+}
+
+type apiResponse struct {
+	Status   string       `json:"status"`
+	Errors   []apiMessage `json:"errors"`
+	Warnings []apiMessage `json:"warnings"`
+}
+
+type apiMessage struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+}
+
+func (r apiResponse) asError() error {
+	if r.Status == "success" || r.Status == "pending" {
+		return nil
+	}
+
+	var texts []string
+	for _, e := range r.Errors {
+		texts = append(texts, e.Text)
+	}
+
+	return &APIError{Status: r.Status, Messages: texts}
+}
+
+// APIError is returned when hosting.de reports a non-success status.
+type APIError struct {
+	Status   string
+	Messages []string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status %s: %s", e.Status, strings.Join(e.Messages, "; "))
+}
+
+// IsPendingZoneUpdate reports whether err is hosting.de's "there is still a pending zone update"
+// conflict, returned when a zoneUpdate call overlaps with another one still being processed.
+func IsPendingZoneUpdate(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	for _, msg := range apiErr.Messages {
+		if strings.Contains(strings.ToLower(msg), "pending zone update") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ZonesFind looks up the zone (and its current records) by its exact name.
+func (c *Client) ZonesFind(ctx context.Context, zoneName string) (ZoneConfig, []Record, error) {
+	req := findRequest{
+		AuthToken: c.apiKey,
+		Filter:    findFilter{Field: "zoneName", Value: zoneName},
+		Limit:     1,
+		Page:      1,
+	}
+
+	var resp zoneConfigsFindResponse
+	if err := c.do(ctx, "zoneConfigsFind", req, &resp); err != nil {
+		return ZoneConfig{}, nil, err
+	}
+
+	if len(resp.Response.Data) == 0 {
+		return ZoneConfig{}, nil, fmt.Errorf("zone not found: %s", zoneName)
+	}
+
+	data := resp.Response.Data[0]
+
+	return data.Zone, data.Records, nil
+}
+
+// ZoneUpdate applies the given record deltas to zone and returns the resulting zone config.
+func (c *Client) ZoneUpdate(ctx context.Context, zone ZoneConfig, recordsToAdd, recordsToDelete []Record) (ZoneConfig, error) {
+	req := updateRequest{
+		AuthToken:       c.apiKey,
+		ZoneConfig:      zone,
+		RecordsToAdd:    recordsToAdd,
+		RecordsToDelete: recordsToDelete,
+	}
+
+	var resp zoneUpdateResponse
+	if err := c.do(ctx, "zoneUpdate", req, &resp); err != nil {
+		return ZoneConfig{}, err
+	}
+
+	return resp.Response.Zone, nil
+}
+
+func (c *Client) do(ctx context.Context, action string, payload, result any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(c.baseURL, "/") + "/" + action
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(rawBody))
+	}
+
+	if err := json.Unmarshal(rawBody, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	base, ok := result.(interface{ asError() error })
+	if !ok {
+		return nil
+	}
+
+	return base.asError()
+}